@@ -0,0 +1,222 @@
+// Package cache is a content-addressed, memory-bounded LRU for the
+// screenshot/DOM/CSS artifacts produced by a story check. Keying on a
+// fingerprint of the story's inputs lets `visage check` skip launching
+// chromedp entirely when nothing relevant has changed since the last run,
+// borrowing the memory-limited eviction idea from Hugo's dynacache.
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/pbnjay/memory"
+)
+
+// Entry is the cached output of a single story/viewport check.
+type Entry struct {
+	Screenshot []byte
+	Dom string
+	Style string
+}
+
+func (e Entry) size() uint64 {
+	return uint64(len(e.Screenshot) + len(e.Dom) + len(e.Style))
+}
+
+type entryRecord struct {
+	key string
+	entry Entry
+}
+
+// Cache is an in-memory LRU over Entry, backed by an on-disk sidecar so a
+// fresh process starts warm instead of re-screenshotting everything.
+type Cache struct {
+	mu sync.Mutex
+	memoryLimit uint64
+	usedMemory uint64
+	order *list.List
+	items map[string]*list.Element
+	diskRoot string
+}
+
+// New creates a Cache that evicts once usedMemory exceeds memoryLimit and
+// persists entries under diskRoot.
+func New(diskRoot string, memoryLimit uint64) *Cache {
+	return &Cache{
+		memoryLimit: memoryLimit,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+		diskRoot: diskRoot,
+	}
+}
+
+// DefaultMemoryLimit resolves the memory budget: the VISAGE_MEMORYLIMIT env
+// var (gigabytes) takes precedence, otherwise a quarter of total system
+// memory as reported by the OS.
+func DefaultMemoryLimit() uint64 {
+	if raw := os.Getenv("VISAGE_MEMORYLIMIT"); raw != "" {
+		if gb, err := strconv.ParseFloat(raw, 64); err == nil && gb > 0 {
+			return uint64(gb * 1024 * 1024 * 1024)
+		}
+	}
+
+	return memory.TotalMemory() / 4
+}
+
+// Key fingerprints the inputs that affect a story's rendered output: its
+// path, the on-disk mtime of that path (in unix seconds), a hash of its
+// transitive dependency set's contents, the viewport being checked, and a
+// hash of the active config. depsHash must change whenever anything the
+// story imports changes, or an edit to a dependency with no effect on the
+// story file's own mtime will hit a stale cache entry.
+func Key(storyPath string, storyMtime int64, depsHash string, viewport string, configHash string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%d|%s|%s|%s", storyPath, storyMtime, depsHash, viewport, configHash)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashConfig returns a stable hash of any JSON-serializable config value,
+// used as part of Key so changing base_url/root_element/viewports busts it.
+func HashConfig(config any) (string, error) {
+	content, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Get returns the cached entry for key, loading it from disk on a
+// memory-cache miss, and promotes it to most-recently-used.
+func (c *Cache) Get(key string) (*Entry, bool) {
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		c.order.MoveToFront(el)
+		entry := el.Value.(*entryRecord).entry
+		c.mu.Unlock()
+		return &entry, true
+	}
+	c.mu.Unlock()
+
+	entry, ok, err := c.readSidecar(key)
+	if err != nil || !ok {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	c.insert(key, entry)
+	c.mu.Unlock()
+
+	return &entry, true
+}
+
+// Set stores entry under key, evicting least-recently-used entries until
+// the cache fits within memoryLimit, and writes a disk sidecar so the
+// entry survives a restart.
+func (c *Cache) Set(key string, entry Entry) error {
+	c.mu.Lock()
+	c.insert(key, entry)
+	c.mu.Unlock()
+
+	return c.writeSidecar(key, entry)
+}
+
+func (c *Cache) insert(key string, entry Entry) {
+	if el, ok := c.items[key]; ok {
+		c.usedMemory -= el.Value.(*entryRecord).entry.size()
+		el.Value = &entryRecord{key: key, entry: entry}
+		c.order.MoveToFront(el)
+	} else {
+		el := c.order.PushFront(&entryRecord{key: key, entry: entry})
+		c.items[key] = el
+	}
+
+	c.usedMemory += entry.size()
+	c.evict()
+}
+
+func (c *Cache) evict() {
+	for c.memoryLimit > 0 && c.usedMemory > c.memoryLimit {
+		oldest := c.order.Back()
+		if oldest == nil {
+			return
+		}
+
+		record := oldest.Value.(*entryRecord)
+		c.usedMemory -= record.entry.size()
+		c.order.Remove(oldest)
+		delete(c.items, record.key)
+	}
+}
+
+func (c *Cache) sidecarDir(key string) string {
+	return filepath.Join(c.diskRoot, key[:2])
+}
+
+func (c *Cache) metaPath(key string) string {
+	return filepath.Join(c.sidecarDir(key), key+".json")
+}
+
+func (c *Cache) screenshotPath(key string) string {
+	return filepath.Join(c.sidecarDir(key), key+".png")
+}
+
+type sidecarMeta struct {
+	Dom string `json:"dom"`
+	Style string `json:"style"`
+}
+
+func (c *Cache) writeSidecar(key string, entry Entry) error {
+	if c.diskRoot == "" {
+		return nil
+	}
+
+	dir := c.sidecarDir(key)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	meta, err := json.Marshal(sidecarMeta{Dom: entry.Dom, Style: entry.Style})
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(c.metaPath(key), meta, 0o644); err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.screenshotPath(key), entry.Screenshot, 0o644)
+}
+
+func (c *Cache) readSidecar(key string) (Entry, bool, error) {
+	if c.diskRoot == "" {
+		return Entry{}, false, nil
+	}
+
+	metaContent, err := os.ReadFile(c.metaPath(key))
+	if os.IsNotExist(err) {
+		return Entry{}, false, nil
+	} else if err != nil {
+		return Entry{}, false, err
+	}
+
+	var meta sidecarMeta
+	if err := json.Unmarshal(metaContent, &meta); err != nil {
+		return Entry{}, false, err
+	}
+
+	screenshot, err := os.ReadFile(c.screenshotPath(key))
+	if err != nil {
+		return Entry{}, false, err
+	}
+
+	return Entry{Screenshot: screenshot, Dom: meta.Dom, Style: meta.Style}, true, nil
+}
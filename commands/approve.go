@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maxischmaxi/visage/core"
+)
+
+func newApproveCommand() *cobra.Command {
+	var component string
+
+	cmd := &cobra.Command{
+		Use: "approve [path]",
+		Short: "Re-run every story and promote its result to the baseline",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := core.GetCwdDir()
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			commandeer, err := NewCommandeer()
+			if err != nil {
+				return err
+			}
+			defer commandeer.Close()
+
+			if err := commandeer.Config.Approve(commandeer.AllocContext(), path, component); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Approved")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&component, "component", "", "only approve results for this component")
+
+	return cmd
+}
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maxischmaxi/visage/core"
+)
+
+func newCheckCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use: "check [path]",
+		Short: "Run every story and diff it against its baseline",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := core.GetCwdDir()
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			commandeer, err := NewCommandeer()
+			if err != nil {
+				return err
+			}
+			defer commandeer.Close()
+
+			results, err := commandeer.Config.Check(commandeer.AllocContext(), path, force)
+			if err != nil {
+				return err
+			}
+
+			for _, result := range results {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: %s / %s [%s]\n", result.Status, result.ComponentName, result.StoryName, result.CurrentTest.Viewport)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "re-check every story, ignoring unchanged dependencies")
+
+	return cmd
+}
@@ -0,0 +1,72 @@
+// Package commands wires the visage CLI's cobra command tree to the core
+// package. check, approve and serve run against a Commandeer instead of
+// reaching for globals; each builds its own with NewCommandeer from the
+// visage.json found for the working directory, and none takes one from
+// its caller. init, report and list-stories use no Commandeer.
+package commands
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/chromedp/chromedp"
+
+	"github.com/maxischmaxi/visage/core"
+)
+
+// Commandeer holds everything a command needs to run: the resolved
+// environment and config, a chromedp allocator context shared across
+// commands that launch a browser, and a logger. Nothing here is global —
+// a caller builds one per invocation (or per test).
+type Commandeer struct {
+	Env *core.Environment
+	Config *core.Config
+	Logger *log.Logger
+
+	allocCtx context.Context
+	allocCancel context.CancelFunc
+}
+
+// NewCommandeer resolves the environment and loads its config, returning
+// an error instead of exiting so callers can decide how to report it.
+func NewCommandeer() (*Commandeer, error) {
+	env, err := core.GetEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := core.LoadConfig(env.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Commandeer{
+		Env: env,
+		Config: config,
+		Logger: log.New(os.Stderr, "", 0),
+	}, nil
+}
+
+// AllocContext lazily starts a chromedp exec allocator and returns a
+// context derived from it, reusing the same allocator across calls within
+// one Commandeer. Each core.Config.Check run starts one browser from this
+// context and opens one tab per story in it, so a run shares a single
+// browser process instead of launching one per story; separate runs (e.g.
+// check then approve) each start their own browser.
+func (c *Commandeer) AllocContext() context.Context {
+	if c.allocCtx == nil {
+		allocCtx, cancel := chromedp.NewExecAllocator(context.Background(), chromedp.DefaultExecAllocatorOptions[:]...)
+		c.allocCtx = allocCtx
+		c.allocCancel = cancel
+	}
+
+	return c.allocCtx
+}
+
+// Close releases the chromedp allocator, if one was started.
+func (c *Commandeer) Close() {
+	if c.allocCancel != nil {
+		c.allocCancel()
+	}
+}
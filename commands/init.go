@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maxischmaxi/visage/core"
+)
+
+func newInitCommand() *cobra.Command {
+	return &cobra.Command{
+		Use: "init",
+		Short: "Write a default visage.json in the current directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd := core.GetCwdDir()
+			path := filepath.Join(cwd, "visage.json")
+
+			if core.FileExists(path) {
+				return fmt.Errorf("%s already exists", path)
+			}
+
+			config := core.Config{
+				BaseURL: "http://localhost:6006/",
+				RootElement: "#storybook-root",
+				MaxThreads: 4,
+				Viewports: core.DefaultViewports,
+			}
+
+			content, err := json.MarshalIndent(config, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			if err := os.WriteFile(path, content, 0o644); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Wrote", path)
+			return nil
+		},
+	}
+}
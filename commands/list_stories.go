@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maxischmaxi/visage/core"
+)
+
+func newListStoriesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use: "list-stories [path]",
+		Short: "List every story visage has discovered",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := core.GetCwdDir()
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			stories, err := core.GetAllStories(path)
+			if err != nil {
+				return err
+			}
+
+			for _, story := range stories {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s / %s (%s)\n", story.ComponentName, story.Name, story.GetOrganismTypeString())
+			}
+
+			return nil
+		},
+	}
+}
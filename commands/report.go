@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maxischmaxi/visage/core"
+)
+
+func newReportCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use: "report [path]",
+		Short: "Generate a self-contained HTML report from the last check",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := core.GetCwdDir()
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			outputPath := output
+			if outputPath == "" {
+				outputPath = filepath.Join(path, "report.html")
+			}
+
+			if err := core.GenerateReport(path, outputPath); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Report written to", outputPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&output, "output", "", "where to write report.html (default: <path>/report.html)")
+
+	return cmd
+}
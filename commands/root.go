@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewRootCommand builds the visage command tree. check, approve and serve
+// each build their own Commandeer when they run, so RunE stays free of
+// package-level state; init, report and list-stories build none.
+func NewRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use: "visage",
+		Short: "Visual regression testing for component libraries",
+		SilenceUsage: true,
+	}
+
+	root.AddCommand(
+		newInitCommand(),
+		newCheckCommand(),
+		newApproveCommand(),
+		newReportCommand(),
+		newServeCommand(),
+		newListStoriesCommand(),
+	)
+
+	return root
+}
+
+// Execute runs the visage command tree against os.Args.
+func Execute() error {
+	return NewRootCommand().Execute()
+}
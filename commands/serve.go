@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maxischmaxi/visage/core"
+	"github.com/maxischmaxi/visage/server"
+)
+
+func newServeCommand() *cobra.Command {
+	var port int
+
+	cmd := &cobra.Command{
+		Use: "serve [path]",
+		Short: "Serve a live dashboard of check results",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := core.GetCwdDir()
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			commandeer, err := NewCommandeer()
+			if err != nil {
+				return err
+			}
+			defer commandeer.Close()
+
+			srv := server.New(path, commandeer.Config, commandeer.AllocContext())
+			return srv.ListenAndServe(fmt.Sprintf(":%d", port))
+		},
+	}
+
+	cmd.Flags().IntVar(&port, "port", 4747, "port to serve the dashboard on")
+
+	return cmd
+}
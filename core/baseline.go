@@ -0,0 +1,96 @@
+package core
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Baseline stores and retrieves the last-known-good RegressionTest for a
+// given component/story/viewport so Story.Check can diff against it.
+type Baseline interface {
+	Load(component, story, viewport string) (*RegressionTest, error)
+	LoadScreenshot(component, story, viewport string) ([]byte, error)
+	Save(component, story, viewport string, test RegressionTest, screenshot []byte) error
+}
+
+// FilesystemBaseline persists baselines under <Root>/<component>/<story>/<viewport>.json,
+// with the approved screenshot written alongside as <viewport>.png.
+type FilesystemBaseline struct {
+	Root string
+}
+
+func NewFilesystemBaseline(root string) *FilesystemBaseline {
+	return &FilesystemBaseline{Root: root}
+}
+
+func (b *FilesystemBaseline) dir(component, story string) string {
+	return filepath.Join(b.Root, component, story)
+}
+
+func (b *FilesystemBaseline) jsonPath(component, story, viewport string) string {
+	return filepath.Join(b.dir(component, story), viewport+".json")
+}
+
+func (b *FilesystemBaseline) pngPath(component, story, viewport string) string {
+	return filepath.Join(b.dir(component, story), viewport+".png")
+}
+
+func (b *FilesystemBaseline) Load(component, story, viewport string) (*RegressionTest, error) {
+	path := b.jsonPath(component, story, viewport)
+	if !FileExists(path) {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var test RegressionTest
+	if err := json.Unmarshal(content, &test); err != nil {
+		return nil, err
+	}
+
+	return &test, nil
+}
+
+func (b *FilesystemBaseline) LoadScreenshot(component, story, viewport string) ([]byte, error) {
+	path := b.pngPath(component, story, viewport)
+	if !FileExists(path) {
+		return nil, nil
+	}
+
+	return os.ReadFile(path)
+}
+
+func (b *FilesystemBaseline) Save(component, story, viewport string, test RegressionTest, screenshot []byte) error {
+	dir := b.dir(component, story)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(test, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(b.jsonPath(component, story, viewport), content, 0o644); err != nil {
+		return err
+	}
+
+	if screenshot != nil {
+		if err := os.WriteFile(b.pngPath(component, story, viewport), screenshot, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,875 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/camelcase"
+	"github.com/haochi/blockhash-go"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+
+	"github.com/maxischmaxi/visage/cache"
+	"github.com/maxischmaxi/visage/report"
+)
+
+const (
+	ORGANISM_TYPE_DRAFT = "draft"
+	ORGANISM_TYPE_FOUNDATION = "foundation"
+	ORGANISM_TYPE_ATOM = "atom"
+	ORGANISM_TYPE_MOLECULE = "molecule"
+	ORGANISM_TYPE_ORGANISM = "organism"
+	ORGANISM_TYPE_TEMPLATE = "template"
+	ORGANISM_TYPE_PAGE = "page"
+)
+
+const (
+	REGRESSION_TEST_STATUS_CREATED = "created"
+	REGRESSION_TEST_STATUS_FAILED = "failed"
+	REGRESSION_TEST_STATUS_PASSED = "passed"
+	REGRESSION_TEST_STATUS_SKIPPED = "skipped"
+)
+
+type Environment struct {
+	Cwd string
+	Home string
+	ConfigPath string
+}
+
+type Story struct {
+	Path string
+	Name string
+	OrganismType string
+	ComponentName string
+	// Viewports, when non-empty, restricts this story to the named Config
+	// viewports (set via an `// @visage-viewport: mobile,desktop` comment).
+	Viewports []string
+	// Deps is this story's own file plus the transitive set of local source
+	// files it imports, used to skip re-checking stories nothing changed under.
+	Deps []string
+}
+
+// depsKey identifies s in a DepsIndex, independent of viewport.
+func (s *Story) depsKey() string {
+	return s.ComponentName + "/" + s.Name
+}
+
+// Viewport describes one emulated browser window a story is screenshotted in.
+type Viewport struct {
+	Name string `json:"name" bson:"name" yaml:"name"`
+	Width int64 `json:"width" bson:"width" yaml:"width"`
+	Height int64 `json:"height" bson:"height" yaml:"height"`
+	DeviceScaleFactor float64 `json:"device_scale_factor,omitempty" bson:"device_scale_factor,omitempty" yaml:"device_scale_factor,omitempty"`
+	UserAgent string `json:"user_agent,omitempty" bson:"user_agent,omitempty" yaml:"user_agent,omitempty"`
+}
+
+type RegressionTestResult struct {
+	Status string
+	ComponentName string
+	StoryName string
+	CurrentTest RegressionTest
+	ExpectedTest RegressionTest
+	Screenshot []byte
+	// DiffImage is a magenta-highlighted perceptual diff PNG, set only when
+	// Status is REGRESSION_TEST_STATUS_FAILED and the failure is visual.
+	DiffImage []byte
+}
+
+type RegressionTest struct {
+	Component string
+	Viewport string
+	DomHash string
+	StyleHash string
+	VisualHash string
+	TimeStamp int64
+}
+
+type Config struct {
+	BaseURL string `json:"base_url" bson:"base_url" yaml:"base_url"`
+	RootElement string `json:"root_element" bson:"root_element" yaml:"root_element"`
+	MaxThreads int `json:"max_threads" bson:"max_threads" yaml:"max_threads"`
+	Viewports []Viewport `json:"viewports" bson:"viewports" yaml:"viewports"`
+	// MemoryLimitGB sets the cache's memory budget when VISAGE_MEMORYLIMIT
+	// is unset; 0 falls back to a quarter of system memory.
+	MemoryLimitGB float64 `json:"memory_limit_gb,omitempty" bson:"memory_limit_gb,omitempty" yaml:"memory_limit_gb,omitempty"`
+}
+
+// memoryLimit resolves the cache's memory budget. VISAGE_MEMORYLIMIT always
+// wins, then the config's memory_limit_gb, then a quarter of system memory.
+func (c *Config) memoryLimit() uint64 {
+	if os.Getenv("VISAGE_MEMORYLIMIT") != "" {
+		return cache.DefaultMemoryLimit()
+	}
+	if c.MemoryLimitGB > 0 {
+		return uint64(c.MemoryLimitGB * 1024 * 1024 * 1024)
+	}
+	return cache.DefaultMemoryLimit()
+}
+
+// DefaultViewports is used when a project's visage.json declares none.
+var DefaultViewports = []Viewport{
+	{Name: "full", Width: 1280, Height: 720},
+}
+
+func (v *Viewport) deviceScaleFactor() float64 {
+	if v.DeviceScaleFactor == 0 {
+		return 1
+	}
+	return v.DeviceScaleFactor
+}
+
+func filterViewports(viewports []Viewport, names []string) []Viewport {
+	allowed := make(map[string]bool, len(names))
+	for _, name := range names {
+		allowed[name] = true
+	}
+
+	var filtered []Viewport
+	for _, viewport := range viewports {
+		if allowed[viewport.Name] {
+			filtered = append(filtered, viewport)
+		}
+	}
+
+	return filtered
+}
+
+// cacheKey fingerprints this story's inputs for the given viewport: its
+// source path, that file's mtime, a hash of its transitive dependencies
+// (s.Deps), the viewport name, and a hash of config. Including the deps
+// hash matters because editing a dependency (e.g. a component a story
+// imports) doesn't touch the story file's own mtime, so without it the
+// cache would keep serving the pre-edit screenshot.
+func (s *Story) cacheKey(config *Config, viewport Viewport) (string, error) {
+	info, err := os.Stat(s.Path)
+	if err != nil {
+		return "", err
+	}
+
+	depsHash, err := HashFiles(s.Deps)
+	if err != nil {
+		return "", err
+	}
+
+	configHash, err := cache.HashConfig(config)
+	if err != nil {
+		return "", err
+	}
+
+	return cache.Key(s.Path, info.ModTime().Unix(), depsHash, viewport.Name, configHash), nil
+}
+
+func (s *Story) GetOrganismTypeString() string {
+	switch s.OrganismType {
+	case ORGANISM_TYPE_ATOM:
+		return "atoms"
+	case ORGANISM_TYPE_MOLECULE:
+		return "molecules"
+	case ORGANISM_TYPE_ORGANISM:
+		return "organisms"
+	case ORGANISM_TYPE_TEMPLATE:
+		return "templates"
+	case ORGANISM_TYPE_PAGE:
+		return "pages"
+	case ORGANISM_TYPE_FOUNDATION:
+		return "foundations"
+	case ORGANISM_TYPE_DRAFT:
+		return "drafts"
+	default:
+		return "drafts"
+	}
+}
+
+func (s *Story) viewports(config *Config) []Viewport {
+	viewports := config.Viewports
+	if len(viewports) == 0 {
+		viewports = DefaultViewports
+	}
+	if len(s.Viewports) > 0 {
+		viewports = filterViewports(viewports, s.Viewports)
+	}
+	return viewports
+}
+
+// TrySkip reports whether s can be skipped entirely this run: its deps
+// must be byte-identical to what deps last recorded for it, and every
+// applicable viewport must already have an approved baseline to reuse. ok
+// is false when deps changed, when a viewport has no baseline, or when err
+// is non-nil; Config.CheckStream runs the real check in the first two
+// cases and reports err in the last.
+func (s *Story) TrySkip(config *Config, baseline Baseline, deps *DepsIndex) ([]RegressionTestResult, bool, error) {
+	unchanged, err := deps.Unchanged(s.depsKey(), s.Deps)
+	if err != nil || !unchanged {
+		return nil, false, err
+	}
+
+	return s.skippedResults(baseline, s.viewports(config))
+}
+
+// Check screenshots s once per applicable Config.Viewports entry and diffs
+// each against its baseline, returning one RegressionTestResult per
+// viewport, then records s.Deps as the fingerprint for the next TrySkip.
+func (s *Story) Check(ctx context.Context, config *Config, baseline Baseline, store *cache.Cache, deps *DepsIndex) ([]RegressionTestResult, error) {
+	var results []RegressionTestResult
+	for _, viewport := range s.viewports(config) {
+		result, err := s.checkViewport(ctx, config, baseline, store, viewport)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+
+	if err := deps.Update(s.depsKey(), s.Deps); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// skippedResults builds a REGRESSION_TEST_STATUS_SKIPPED result per
+// viewport reusing the prior baseline. ok is false (with no error) if any
+// viewport has no baseline yet, since there is nothing to reuse.
+func (s *Story) skippedResults(baseline Baseline, viewports []Viewport) ([]RegressionTestResult, bool, error) {
+	results := make([]RegressionTestResult, 0, len(viewports))
+
+	for _, viewport := range viewports {
+		prior, err := baseline.Load(s.ComponentName, s.Name, viewport.Name)
+		if err != nil {
+			return nil, false, err
+		}
+		if prior == nil {
+			return nil, false, nil
+		}
+
+		results = append(results, RegressionTestResult{
+			Status: REGRESSION_TEST_STATUS_SKIPPED,
+			ComponentName: s.ComponentName,
+			StoryName: s.Name,
+			CurrentTest: *prior,
+			ExpectedTest: *prior,
+		})
+	}
+
+	return results, true, nil
+}
+
+func (s *Story) checkViewport(ctx context.Context, config *Config, baseline Baseline, store *cache.Cache, viewport Viewport) (*RegressionTestResult, error) {
+	u, err := url.Parse(config.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = "iframe.html"
+	name := camelcase.Split(s.Name)
+	u.RawQuery = fmt.Sprintf("globals=viewport:%s&args=&id=%s-%s--%s&viewMode=story",
+		viewport.Name,
+		s.GetOrganismTypeString(),
+		strings.ToLower(s.ComponentName),
+		strings.ToLower(strings.Join(name, "-")),
+	)
+
+	fmt.Printf("Checking %s %s [%s] %s\n", s.ComponentName, s.Name, viewport.Name, u.String())
+
+	var screenshot []byte
+	var domHash string
+	var visualHash string
+	var styleHash string
+	var html string
+	var style string
+
+	cacheKey, err := s.cacheKey(config, viewport)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry, hit := store.Get(cacheKey); hit {
+		fmt.Println("Cache hit:", cacheKey)
+		screenshot, html, style = entry.Screenshot, entry.Dom, entry.Style
+	} else {
+		id, _ := strings.CutPrefix(config.RootElement, "#")
+		fmt.Println("ID:", id)
+		htmlQuery := fmt.Sprintf("document.querySelector(\"#%s\").outerHTML", id)
+		cssQuery := "document.styleSheets[0].cssRules[0].cssText"
+		fmt.Println("HTML Query:", htmlQuery)
+		fmt.Println("CSS Query:", cssQuery)
+
+		tasks := chromedp.Tasks{
+			chromedp.EmulateViewport(viewport.Width, viewport.Height, chromedp.EmulateScale(viewport.deviceScaleFactor())),
+		}
+		if viewport.UserAgent != "" {
+			tasks = append(tasks, chromedp.ActionFunc(func(ctx context.Context) error {
+				return emulation.SetUserAgentOverride(viewport.UserAgent).Do(ctx)
+			}))
+		}
+		tasks = append(tasks,
+			chromedp.Navigate(u.String()),
+			chromedp.WaitVisible(id, chromedp.ByID),
+			chromedp.WaitReady(id, chromedp.ByID),
+			chromedp.Sleep(1*time.Second),
+			chromedp.Screenshot(id, &screenshot, chromedp.ByID),
+			chromedp.EvaluateAsDevTools(htmlQuery, &html),
+			chromedp.EvaluateAsDevTools(cssQuery, &style),
+		)
+
+		if err := chromedp.Run(ctx, tasks); err != nil {
+			fmt.Println("Error:", err)
+			return nil, err
+		}
+
+		if err := store.Set(cacheKey, cache.Entry{Screenshot: screenshot, Dom: html, Style: style}); err != nil {
+			return nil, err
+		}
+	}
+
+	reader := bytes.NewReader(screenshot)
+	vh, err := blockhash.Blockhash(reader, 16)
+	if err != nil {
+		return nil, err
+	}
+	visualHash = vh.ToHex()
+
+	shaHash := sha1.New()
+	shaHash.Write([]byte(style))
+	styleHash = hex.EncodeToString(shaHash.Sum(nil))
+
+	md5Hash := md5.New()
+	md5Hash.Write([]byte(html))
+	domHash = hex.EncodeToString(md5Hash.Sum(nil))
+
+	test := RegressionTest{
+		Component: s.Name,
+		Viewport: viewport.Name,
+		DomHash: domHash,
+		StyleHash: styleHash,
+		VisualHash: visualHash,
+		TimeStamp: time.Now().Unix(),
+	}
+
+	expected, err := baseline.Load(s.ComponentName, s.Name, test.Viewport)
+	if err != nil {
+		return nil, err
+	}
+
+	result := RegressionTestResult{
+		ComponentName: s.ComponentName,
+		StoryName: s.Name,
+		CurrentTest: test,
+		Screenshot: screenshot,
+	}
+
+	if expected == nil {
+		result.Status = REGRESSION_TEST_STATUS_CREATED
+		result.ExpectedTest = test
+	} else {
+		result.ExpectedTest = *expected
+
+		expectedScreenshot, err := baseline.LoadScreenshot(s.ComponentName, s.Name, test.Viewport)
+		if err != nil {
+			return nil, err
+		}
+
+		visualDiff, err := PerceptualDiff(expected.VisualHash, test.VisualHash, expectedScreenshot, screenshot)
+		if err != nil {
+			return nil, err
+		}
+
+		if expected.DomHash == test.DomHash && expected.StyleHash == test.StyleHash && visualDiff.Equal {
+			result.Status = REGRESSION_TEST_STATUS_PASSED
+		} else {
+			result.Status = REGRESSION_TEST_STATUS_FAILED
+			result.DiffImage = visualDiff.Image
+		}
+	}
+
+	return &result, nil
+}
+
+func LoadConfig(path string) (*Config, error) {
+    jsonFile, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer jsonFile.Close()
+
+	byteValue, err := io.ReadAll(jsonFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	err = json.Unmarshal(byteValue, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("base_url is required")
+	}
+
+	return &config, nil
+}
+
+func GetHomeDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		panic(err)
+	}
+	return homeDir
+}
+
+func GetCwdDir() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	return cwd
+}
+
+func FileExists(path string) bool {
+	_, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false
+	}
+	return err == nil
+}
+
+func ReadFileToString(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+func GetIgnoreFiles(path string) ([]string, error) {
+	ignoreFiles := []string{
+		"patches",
+		"node_modules",
+		".DS_Store",
+		".idea",
+		".vscode",
+		"dist",
+		"build",
+		"coverage",
+		"out",
+		"tmp",
+		"temp",
+		"*.log",
+		"*.tmp",
+	}
+
+	path1 := filepath.Join(path, ".gitignore")
+	path2 := filepath.Join(path, "..", ".gitignore")
+
+	var content string
+
+	if FileExists(path1) {
+		c, err := ReadFileToString(path1)
+		if err != nil {
+			return nil, err
+		}
+
+		content = c
+	} else if FileExists(path2) {
+		c, err := ReadFileToString(path2)
+		if err != nil {
+			return nil, err
+		}
+
+		content = c
+	} else {
+		return ignoreFiles, nil
+	}
+
+	for line := range strings.SplitSeq(content, "\n") {
+		if line != "" && line[0] != '#' {
+			ignoreFiles = append(ignoreFiles, line)
+		}
+	}
+	
+	// Remove duplicates
+	ignoreFilesMap := make(map[string]bool)
+	for _, line := range ignoreFiles {
+		ignoreFilesMap[line] = true
+	}
+
+	// Convert map keys back to slice
+	uniqueIgnoreFiles := make([]string, 0, len(ignoreFilesMap))
+	for line := range ignoreFilesMap {
+		uniqueIgnoreFiles = append(uniqueIgnoreFiles, line)
+	}
+
+	return uniqueIgnoreFiles, nil
+}
+
+func WalkDir(path string, ignores []string) ([]string, error) {
+	var files []string
+
+	err := filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			for _, ignore := range ignores {
+				if strings.Contains(filePath, ignore) {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+
+		for _, ignore := range ignores {
+			if strings.Contains(filePath, ignore) {
+				return nil
+			}
+		}
+
+		files = append(files, filePath)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+var storyRegexp = regexp.MustCompile(`export const (\w+): Story`)
+var viewportCommentRegexp = regexp.MustCompile(`//\s*@visage-viewport:\s*(.+)`)
+
+// storyMatch is a story export alongside any `@visage-viewport` comment
+// found directly above it in the source file.
+type storyMatch struct {
+	name string
+	viewports []string
+}
+
+// parseStoryMatches walks content line by line so a `@visage-viewport`
+// comment is attributed to the story export that immediately follows it.
+func parseStoryMatches(content string) []storyMatch {
+	var matches []storyMatch
+	var pendingViewports []string
+
+	for _, line := range strings.Split(content, "\n") {
+		if m := viewportCommentRegexp.FindStringSubmatch(line); m != nil {
+			pendingViewports = nil
+			for _, name := range strings.Split(m[1], ",") {
+				pendingViewports = append(pendingViewports, strings.TrimSpace(name))
+			}
+			continue
+		}
+
+		if m := storyRegexp.FindStringSubmatch(line); m != nil {
+			matches = append(matches, storyMatch{name: m[1], viewports: pendingViewports})
+			pendingViewports = nil
+		}
+	}
+
+	return matches
+}
+
+func GetAllStories(path string) ([]Story, error) {
+    ignores, err := GetIgnoreFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var stories []Story
+
+	paths, err := WalkDir(path, ignores)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range paths {
+		content, err := ReadFileToString(p)
+		if err != nil {
+			return nil, err
+		}
+
+		matches := parseStoryMatches(content)
+
+		deps, err := BuildDependencyGraph(p, path)
+		if err != nil {
+			return nil, err
+		}
+
+		var componentName string
+		parts := strings.Split(p, "/")
+		if len(parts) <= 0 {
+			return nil, fmt.Errorf("invalid path: %s", p)
+		}
+		lastPart := parts[len(parts)-1]
+		fileParts := strings.Split(lastPart, ".")
+		if len(fileParts) <= 0 {
+			return nil, fmt.Errorf("invalid file name: %s", lastPart)
+		}
+		componentName = fileParts[0]
+
+		if len(matches) > 0 {
+			for _, match := range matches {
+				var organismType string
+				if strings.Contains(p, "99-drafts") {
+					organismType = ORGANISM_TYPE_DRAFT
+				} else if strings.Contains(p, "00-foundations") {
+					organismType = ORGANISM_TYPE_FOUNDATION
+				} else if strings.Contains(p, "10-atoms") {
+					organismType = ORGANISM_TYPE_ATOM
+				} else if strings.Contains(p, "20-molecules") {
+					organismType = ORGANISM_TYPE_MOLECULE
+				} else if strings.Contains(p, "30-organisms") {
+					organismType = ORGANISM_TYPE_ORGANISM
+				} else if strings.Contains(p, "40-templates") {
+					organismType = ORGANISM_TYPE_TEMPLATE
+				} else if strings.Contains(p, "50-pages") {
+					organismType = ORGANISM_TYPE_PAGE
+				} else {
+					organismType = ORGANISM_TYPE_DRAFT
+				}
+
+				story := Story{
+					Name: match.name,
+					Path: p,
+					ComponentName: componentName,
+					OrganismType: organismType,
+					Viewports: match.viewports,
+					Deps: deps,
+				}
+
+				fmt.Printf("Found story: %s %s %s %s\n", story.ComponentName, story.Name, p, organismType)
+
+				stories = append(stories, story)
+			}
+		}
+	}
+
+	return stories, nil
+}
+
+// Check runs every story found under path. Unless force is true, a story
+// whose transitive dependencies are unchanged since the last run and which
+// already has an approved baseline is reported as skipped instead of
+// re-screenshotted. allocCtx must be an allocator context (as returned by
+// chromedp.NewExecAllocator); the run starts one browser from it, on the
+// first story that is not skipped, and every story's tab shares that
+// browser process instead of each launching its own.
+func (c *Config) Check(allocCtx context.Context, path string, force bool) ([]RegressionTestResult, error) {
+	return c.CheckStream(allocCtx, path, force, nil)
+}
+
+// CheckStream behaves like Check, but additionally invokes onResult (if
+// non-nil) for every RegressionTestResult as soon as it's produced, so a
+// caller like the serve dashboard can stream progress instead of waiting
+// for the whole run to finish. Calls to onResult never overlap.
+func (c *Config) CheckStream(allocCtx context.Context, path string, force bool, onResult func(RegressionTestResult)) ([]RegressionTestResult, error) {
+	stories, err := GetAllStories(path)
+	if err != nil {
+		return nil, err
+	}
+
+	packageJsonPath := filepath.Join(path, "package.json")
+	if !FileExists(packageJsonPath) {
+		return nil, fmt.Errorf("package.json not found in %s", path)
+	}
+
+	baseline := NewFilesystemBaseline(filepath.Join(path, ".visage", "baselines"))
+	store := cache.New(filepath.Join(path, ".visage", "cache"), c.memoryLimit())
+
+	deps, err := LoadDepsIndex(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.MaxThreads)
+	results := make(chan []RegressionTestResult, len(stories))
+	errors := make(chan error, len(stories))
+
+	var onResultMu sync.Mutex
+	emit := func(result []RegressionTestResult) {
+		if onResult == nil {
+			return
+		}
+		onResultMu.Lock()
+		defer onResultMu.Unlock()
+		for _, r := range result {
+			onResult(r)
+		}
+	}
+
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+	defer cancelBrowser()
+	var startBrowser sync.Once
+	var startErr error
+
+	for _, story := range stories {
+		wg.Add(1)
+
+		go func(story *Story) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if !force {
+				if skipped, ok, err := story.TrySkip(c, baseline, deps); err != nil {
+					errors <- err
+					return
+				} else if ok {
+					emit(skipped)
+					results <- skipped
+					return
+				}
+			}
+
+			startBrowser.Do(func() { startErr = chromedp.Run(browserCtx) })
+			if startErr != nil {
+				errors <- startErr
+				return
+			}
+
+			tabCtx, cancelCtx := chromedp.NewContext(browserCtx)
+			defer cancelCtx()
+
+			tabCtx, cancelTimeout := context.WithTimeout(tabCtx, 30*time.Second)
+			defer cancelTimeout()
+
+			result, err := story.Check(tabCtx, c, baseline, store, deps)
+			if err != nil {
+				errors <- err
+			} else {
+				emit(result)
+				results <- result
+			}
+		}(&story)
+	}
+
+	wg.Wait()
+	close(results)
+	close(errors)
+
+	var regressionTestResults []RegressionTestResult
+	for result := range results {
+		regressionTestResults = append(regressionTestResults, result...)
+	}
+
+	var regressionTestErrors []error
+	for err := range errors {
+		regressionTestErrors = append(regressionTestErrors, err)
+	}
+
+	if len(regressionTestErrors) > 0 {
+		return nil, fmt.Errorf("errors occurred during regression test: %v", regressionTestErrors)
+	}
+
+	if err := deps.Save(path); err != nil {
+		return nil, err
+	}
+
+	resultsStore := NewResultsStore(filepath.Join(path, ".visage", "results"))
+	for _, result := range regressionTestResults {
+		if err := resultsStore.Save(result); err != nil {
+			return nil, err
+		}
+	}
+
+	return regressionTestResults, nil
+}
+
+// Approve runs the full check and promotes every (filtered) result to the
+// baseline store, overwriting whatever was approved previously.
+func (c *Config) Approve(ctx context.Context, path string, component string) error {
+	results, err := c.Check(ctx, path, true)
+	if err != nil {
+		return err
+	}
+
+	baseline := NewFilesystemBaseline(filepath.Join(path, ".visage", "baselines"))
+
+	for _, result := range results {
+		if component != "" && result.ComponentName != component {
+			continue
+		}
+
+		if err := baseline.Save(result.ComponentName, result.StoryName, result.CurrentTest.Viewport, result.CurrentTest, result.Screenshot); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GenerateReport walks the most recent results for path and writes a
+// self-contained report.html to outputPath.
+func GenerateReport(path string, outputPath string) error {
+	resultsStore := NewResultsStore(filepath.Join(path, ".visage", "results"))
+	baselineRoot := filepath.Join(path, ".visage", "baselines")
+
+	stored, err := resultsStore.List(baselineRoot)
+	if err != nil {
+		return err
+	}
+
+	results := make([]report.Result, 0, len(stored))
+	for _, entry := range stored {
+		results = append(results, report.Result{
+			Component: entry.Component,
+			Story: entry.Story,
+			Viewport: entry.Viewport,
+			Status: entry.Status,
+			ExpectedImagePath: entry.ExpectedPath,
+			ActualImagePath: entry.ActualPath,
+			DiffImagePath: entry.DiffPath,
+		})
+	}
+
+	return report.Generate(results, outputPath)
+}
+
+// GetEnvironment resolves cwd/home and locates the active visage.json,
+// preferring a project-local config over the one in the user's home
+// directory. It returns an error rather than exiting so callers (the
+// commands package) can decide how to report it.
+func GetEnvironment() (*Environment, error) {
+	cwd := GetCwdDir()
+	homeDir := GetHomeDir()
+
+	homeConfigPath := filepath.Join(homeDir, ".config", "visage.json")
+	localConfigPath := filepath.Join(cwd, "visage.json")
+
+	var configPath string
+	if FileExists(localConfigPath) {
+		configPath = localConfigPath
+	} else if FileExists(homeConfigPath) {
+		configPath = homeConfigPath
+	} else {
+		return nil, fmt.Errorf("no config file found in %s or %s", localConfigPath, homeConfigPath)
+	}
+
+	return &Environment{
+		Cwd: cwd,
+		Home: homeDir,
+		ConfigPath: configPath,
+	}, nil
+}
@@ -0,0 +1,265 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var importRegexp = regexp.MustCompile(`(?:import|export)\s+(?:[\s\S]*?\s+from\s+)?["']([^"']+)["']`)
+
+// ParseImports extracts the module specifiers referenced by import/re-export
+// statements in a TS/JS source file.
+func ParseImports(content string) []string {
+	matches := importRegexp.FindAllStringSubmatch(content, -1)
+
+	imports := make([]string, 0, len(matches))
+	for _, match := range matches {
+		imports = append(imports, match[1])
+	}
+
+	return imports
+}
+
+// resolveImportPath turns a relative import specifier into a file on disk,
+// trying the extensions and index files the storybook/TS tooling would.
+// Bare specifiers (packages under node_modules) are left unresolved.
+func resolveImportPath(fromFile, importPath string) (string, bool) {
+	if !strings.HasPrefix(importPath, ".") {
+		return "", false
+	}
+
+	base := filepath.Join(filepath.Dir(fromFile), importPath)
+	candidates := []string{
+		base,
+		base + ".ts",
+		base + ".tsx",
+		base + ".js",
+		base + ".jsx",
+		filepath.Join(base, "index.ts"),
+		filepath.Join(base, "index.tsx"),
+		filepath.Join(base, "index.js"),
+		filepath.Join(base, "index.jsx"),
+	}
+
+	for _, candidate := range candidates {
+		if isRegularFile(candidate) {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+// isRegularFile reports whether path exists and is a file, not a
+// directory. Unlike FileExists, a directory-style import (`./Button`
+// resolving to a `Button/` folder) must fall through to its index
+// candidate rather than being treated as resolved itself.
+func isRegularFile(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return !info.IsDir()
+}
+
+// BuildDependencyGraph walks the transitive local imports reachable from
+// entryPath, staying within projectRoot and stopping at node_modules.
+func BuildDependencyGraph(entryPath string, projectRoot string) ([]string, error) {
+	absRoot, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	absEntry, err := filepath.Abs(entryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	visited := map[string]bool{}
+	queue := []string{absEntry}
+	var deps []string
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		deps = append(deps, current)
+
+		content, err := ReadFileToString(current)
+		if err != nil {
+			continue
+		}
+
+		for _, importPath := range ParseImports(content) {
+			resolved, ok := resolveImportPath(current, importPath)
+			if !ok || strings.Contains(resolved, "node_modules") {
+				continue
+			}
+
+			absResolved, err := filepath.Abs(resolved)
+			if err != nil {
+				continue
+			}
+
+			rel, err := filepath.Rel(absRoot, absResolved)
+			if err != nil || strings.HasPrefix(rel, "..") {
+				continue
+			}
+
+			if !visited[absResolved] {
+				queue = append(queue, absResolved)
+			}
+		}
+	}
+
+	sort.Strings(deps)
+	return deps, nil
+}
+
+// DepsRecord is the dependency fingerprint recorded for one story at the
+// end of its last non-skipped check.
+type DepsRecord struct {
+	Deps map[string]string `json:"deps"`
+}
+
+// DepsIndex maps a "<component>/<story>" key to its DepsRecord and is
+// persisted to .visage/deps.json so `visage check` can skip stories whose
+// transitive dependencies haven't changed since the recorded run.
+type DepsIndex struct {
+	mu sync.RWMutex
+	Stories map[string]DepsRecord `json:"stories"`
+}
+
+func NewDepsIndex() *DepsIndex {
+	return &DepsIndex{Stories: make(map[string]DepsRecord)}
+}
+
+func depsIndexPath(projectPath string) string {
+	return filepath.Join(projectPath, ".visage", "deps.json")
+}
+
+// LoadDepsIndex reads the on-disk deps.json, returning an empty index if it
+// doesn't exist yet.
+func LoadDepsIndex(projectPath string) (*DepsIndex, error) {
+	path := depsIndexPath(projectPath)
+	if !FileExists(path) {
+		return NewDepsIndex(), nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	index := NewDepsIndex()
+	if err := json.Unmarshal(content, index); err != nil {
+		return nil, err
+	}
+	if index.Stories == nil {
+		index.Stories = make(map[string]DepsRecord)
+	}
+
+	return index, nil
+}
+
+// Save persists the index to .visage/deps.json.
+func (idx *DepsIndex) Save(projectPath string) error {
+	idx.mu.RLock()
+	content, err := json.MarshalIndent(idx, "", "  ")
+	idx.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	path := depsIndexPath(projectPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0o644)
+}
+
+// Unchanged reports whether every path in deps hashes to exactly what was
+// recorded for key, meaning the story can be skipped.
+func (idx *DepsIndex) Unchanged(key string, deps []string) (bool, error) {
+	idx.mu.RLock()
+	record, ok := idx.Stories[key]
+	idx.mu.RUnlock()
+	if !ok || len(record.Deps) != len(deps) {
+		return false, nil
+	}
+
+	for _, dep := range deps {
+		recordedHash, ok := record.Deps[dep]
+		if !ok {
+			return false, nil
+		}
+
+		currentHash, err := HashFile(dep)
+		if err != nil {
+			return false, err
+		}
+
+		if currentHash != recordedHash {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Update records the current content hash of every dep under key.
+func (idx *DepsIndex) Update(key string, deps []string) error {
+	hashes := make(map[string]string, len(deps))
+	for _, dep := range deps {
+		hash, err := HashFile(dep)
+		if err != nil {
+			return err
+		}
+		hashes[dep] = hash
+	}
+
+	idx.mu.Lock()
+	idx.Stories[key] = DepsRecord{Deps: hashes}
+	idx.mu.Unlock()
+
+	return nil
+}
+
+func HashFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// HashFiles combines the content hash of every path in deps into a single
+// fingerprint, so callers can detect a change anywhere in a transitive
+// dependency set without storing one hash per file.
+func HashFiles(deps []string) (string, error) {
+	h := sha256.New()
+	for _, dep := range deps {
+		hash, err := HashFile(dep)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s:%s\n", dep, hash)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
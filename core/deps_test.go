@@ -0,0 +1,164 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseImports(t *testing.T) {
+	content := `
+import React from "react";
+import { Button } from './Button';
+export * from "../shared/utils";
+import "./side-effect.css";
+`
+
+	got := ParseImports(content)
+	want := []string{"react", "./Button", "../shared/utils", "./side-effect.css"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseImports() = %#v, want %#v", got, want)
+	}
+}
+
+func TestResolveImportPath(t *testing.T) {
+	dir := t.TempDir()
+	storyPath := filepath.Join(dir, "Button.stories.tsx")
+
+	write(t, filepath.Join(dir, "Button.tsx"), "export const Button = () => null")
+	write(t, filepath.Join(dir, "shared", "index.ts"), "export const helper = () => null")
+
+	resolved, ok := resolveImportPath(storyPath, "./Button")
+	if !ok {
+		t.Fatalf("resolveImportPath(%q) did not resolve", "./Button")
+	}
+	if resolved != filepath.Join(dir, "Button.tsx") {
+		t.Errorf("resolveImportPath(%q) = %q, want %q", "./Button", resolved, filepath.Join(dir, "Button.tsx"))
+	}
+
+	resolved, ok = resolveImportPath(storyPath, "./shared")
+	if !ok {
+		t.Fatalf("resolveImportPath(%q) did not resolve", "./shared")
+	}
+	if resolved != filepath.Join(dir, "shared", "index.ts") {
+		t.Errorf("resolveImportPath(%q) = %q, want %q", "./shared", resolved, filepath.Join(dir, "shared", "index.ts"))
+	}
+
+	if _, ok := resolveImportPath(storyPath, "react"); ok {
+		t.Errorf("resolveImportPath(%q) resolved a bare specifier, want unresolved", "react")
+	}
+
+	if _, ok := resolveImportPath(storyPath, "./DoesNotExist"); ok {
+		t.Errorf("resolveImportPath(%q) resolved a missing file, want unresolved", "./DoesNotExist")
+	}
+}
+
+func write(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll(%q): %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile(%q): %v", path, err)
+	}
+}
+
+func TestBuildDependencyGraph(t *testing.T) {
+	root := t.TempDir()
+
+	write(t, filepath.Join(root, "Button.stories.tsx"), `import { Button } from './Button';`)
+	write(t, filepath.Join(root, "Button.tsx"), `import { theme } from './theme'; export const Button = () => null;`)
+	write(t, filepath.Join(root, "theme.ts"), `export const theme = {};`)
+	write(t, filepath.Join(root, "node_modules", "react", "index.js"), `module.exports = {};`)
+
+	entry := filepath.Join(root, "Button.stories.tsx")
+	deps, err := BuildDependencyGraph(entry, root)
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph returned error: %v", err)
+	}
+
+	want := []string{
+		filepath.Join(root, "Button.stories.tsx"),
+		filepath.Join(root, "Button.tsx"),
+		filepath.Join(root, "theme.ts"),
+	}
+
+	if len(deps) != len(want) {
+		t.Fatalf("BuildDependencyGraph() = %#v, want %#v", deps, want)
+	}
+	for _, path := range want {
+		found := false
+		for _, dep := range deps {
+			if dep == path {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("BuildDependencyGraph() missing %q, got %#v", path, deps)
+		}
+	}
+}
+
+func TestDepsIndexUnchangedAndUpdate(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "theme.ts")
+	write(t, file, "export const theme = {};")
+
+	idx := NewDepsIndex()
+	deps := []string{file}
+
+	unchanged, err := idx.Unchanged("Button/Default", deps)
+	if err != nil {
+		t.Fatalf("Unchanged returned error: %v", err)
+	}
+	if unchanged {
+		t.Errorf("expected Unchanged to be false before any Update")
+	}
+
+	if err := idx.Update("Button/Default", deps); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+
+	unchanged, err = idx.Unchanged("Button/Default", deps)
+	if err != nil {
+		t.Fatalf("Unchanged returned error: %v", err)
+	}
+	if !unchanged {
+		t.Errorf("expected Unchanged to be true right after Update")
+	}
+
+	write(t, file, "export const theme = { color: 'blue' };")
+
+	unchanged, err = idx.Unchanged("Button/Default", deps)
+	if err != nil {
+		t.Fatalf("Unchanged returned error: %v", err)
+	}
+	if unchanged {
+		t.Errorf("expected Unchanged to be false after editing a dependency")
+	}
+}
+
+func TestHashFilesChangesWithContent(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "theme.ts")
+	write(t, file, "export const theme = {};")
+
+	before, err := HashFiles([]string{file})
+	if err != nil {
+		t.Fatalf("HashFiles returned error: %v", err)
+	}
+
+	write(t, file, "export const theme = { color: 'blue' };")
+
+	after, err := HashFiles([]string{file})
+	if err != nil {
+		t.Fatalf("HashFiles returned error: %v", err)
+	}
+
+	if before == after {
+		t.Errorf("expected HashFiles to change after editing a dependency")
+	}
+}
@@ -0,0 +1,180 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"math/bits"
+)
+
+const (
+	// blockhashHammingThreshold is the max Hamming distance between two
+	// blockhash visual hashes before we bother doing a full perceptual diff.
+	blockhashHammingThreshold = 10
+
+	// yiqLumaThreshold is the minimum brightness delta (0-255) for a pixel
+	// to be considered meaningfully changed rather than noise.
+	yiqLumaThreshold = 24.0
+
+	// aaEdgeThreshold is the brightness delta used to decide whether a
+	// pixel sits on an anti-aliased edge in one of its 3x3 neighborhoods.
+	aaEdgeThreshold = 24.0
+)
+
+// DiffResult is the outcome of comparing two screenshots of the same story.
+type DiffResult struct {
+	Equal bool
+	ChangedPixels int
+	TotalPixels int
+	// Image is a magenta-highlighted PNG diff, nil when Equal is true.
+	Image []byte
+}
+
+// PerceptualDiff first rejects on a cheap blockhash Hamming distance, then
+// falls back to a per-pixel YIQ-luma comparison with anti-aliasing
+// rejection so that sub-pixel rendering differences don't flag as failures.
+func PerceptualDiff(expectedHash, actualHash string, expectedPNG, actualPNG []byte) (*DiffResult, error) {
+	distance, err := hammingDistance(expectedHash, actualHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if distance <= blockhashHammingThreshold {
+		return &DiffResult{Equal: true}, nil
+	}
+
+	expectedImg, err := png.Decode(bytes.NewReader(expectedPNG))
+	if err != nil {
+		return nil, err
+	}
+
+	actualImg, err := png.Decode(bytes.NewReader(actualPNG))
+	if err != nil {
+		return nil, err
+	}
+
+	width := max(expectedImg.Bounds().Dx(), actualImg.Bounds().Dx())
+	height := max(expectedImg.Bounds().Dy(), actualImg.Bounds().Dy())
+
+	expectedCanvas := resizeToCanvas(expectedImg, width, height)
+	actualCanvas := resizeToCanvas(actualImg, width, height)
+
+	diffImg := image.NewRGBA(image.Rect(0, 0, width, height))
+	changed := 0
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			expectedColor := expectedCanvas.At(x, y)
+			actualColor := actualCanvas.At(x, y)
+
+			delta := math.Abs(luma(expectedColor) - luma(actualColor))
+			if delta < yiqLumaThreshold {
+				diffImg.Set(x, y, expectedColor)
+				continue
+			}
+
+			if isAntiAliased(expectedCanvas, x, y, width, height) || isAntiAliased(actualCanvas, x, y, width, height) {
+				diffImg.Set(x, y, expectedColor)
+				continue
+			}
+
+			changed++
+			diffImg.Set(x, y, color.RGBA{R: 255, G: 0, B: 255, A: 255})
+		}
+	}
+
+	if changed == 0 {
+		return &DiffResult{Equal: true, TotalPixels: width * height}, nil
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, diffImg); err != nil {
+		return nil, err
+	}
+
+	return &DiffResult{
+		Equal: changed == 0,
+		ChangedPixels: changed,
+		TotalPixels: width * height,
+		Image: buf.Bytes(),
+	}, nil
+}
+
+func hammingDistance(a, b string) (int, error) {
+	aBytes, err := hex.DecodeString(a)
+	if err != nil {
+		return 0, err
+	}
+
+	bBytes, err := hex.DecodeString(b)
+	if err != nil {
+		return 0, err
+	}
+
+	length := min(len(aBytes), len(bBytes))
+	distance := (len(aBytes) - length + len(bBytes) - length) * 8
+
+	for i := 0; i < length; i++ {
+		distance += bits.OnesCount8(aBytes[i] ^ bBytes[i])
+	}
+
+	return distance, nil
+}
+
+func luma(c color.Color) float64 {
+	r, g, b, _ := c.RGBA()
+	return float64(r>>8)*0.299 + float64(g>>8)*0.587 + float64(b>>8)*0.114
+}
+
+// isAntiAliased reports whether (x, y) has at least two 3x3 neighbors with a
+// large brightness delta, which marks it as sitting on a rendered edge
+// rather than a genuine content change.
+func isAntiAliased(img image.Image, x, y, width, height int) bool {
+	center := luma(img.At(x, y))
+	siblings := 0
+
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+
+			nx, ny := x+dx, y+dy
+			if nx < 0 || ny < 0 || nx >= width || ny >= height {
+				continue
+			}
+
+			if math.Abs(center-luma(img.At(nx, ny))) >= aaEdgeThreshold {
+				siblings++
+				if siblings >= 2 {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// resizeToCanvas nearest-neighbor samples img onto a width x height canvas
+// so two screenshots of slightly different sizes can still be diffed.
+func resizeToCanvas(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() == width && bounds.Dy() == height {
+		return img
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy := bounds.Min.Y + y*bounds.Dy()/height
+		for x := 0; x < width; x++ {
+			sx := bounds.Min.X + x*bounds.Dx()/width
+			dst.Set(x, y, img.At(sx, sy))
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestHammingDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a string
+		b string
+		want int
+	}{
+		{name: "identical", a: "ff00", b: "ff00", want: 0},
+		{name: "one bit", a: "00", b: "01", want: 1},
+		{name: "fully opposite bytes", a: "0000", b: "ffff", want: 16},
+		{name: "different lengths", a: "00", b: "0000", want: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hammingDistance(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("hammingDistance(%q, %q) returned error: %v", tt.a, tt.b, err)
+			}
+			if got != tt.want {
+				t.Errorf("hammingDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func solidImage(width, height int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestPerceptualDiffWithinBlockhashThresholdIsEqual(t *testing.T) {
+	white := encodePNG(t, solidImage(4, 4, color.White))
+
+	// Hashes close enough to stay under blockhashHammingThreshold short-
+	// circuit before either PNG is even decoded.
+	result, err := PerceptualDiff("0000", "0000", white, white)
+	if err != nil {
+		t.Fatalf("PerceptualDiff returned error: %v", err)
+	}
+
+	if !result.Equal {
+		t.Errorf("expected Equal true for identical blockhashes, got false")
+	}
+	if result.Image != nil {
+		t.Errorf("expected no diff image when Equal, got %d bytes", len(result.Image))
+	}
+}
+
+func TestPerceptualDiffDetectsChangedInteriorPixels(t *testing.T) {
+	const size = 8
+
+	expected := solidImage(size, size, color.White)
+
+	actual := solidImage(size, size, color.White)
+	for y := 2; y < 6; y++ {
+		for x := 2; x < 6; x++ {
+			actual.Set(x, y, color.Black)
+		}
+	}
+
+	expectedPNG := encodePNG(t, expected)
+	actualPNG := encodePNG(t, actual)
+
+	// Hashes far enough apart to force the full per-pixel comparison.
+	result, err := PerceptualDiff("0000", "ffff", expectedPNG, actualPNG)
+	if err != nil {
+		t.Fatalf("PerceptualDiff returned error: %v", err)
+	}
+
+	if result.Equal {
+		t.Fatalf("expected Equal false for a changed block, got true")
+	}
+	if result.ChangedPixels == 0 {
+		t.Errorf("expected at least one changed pixel, got 0")
+	}
+	if result.TotalPixels != size*size {
+		t.Errorf("TotalPixels = %d, want %d", result.TotalPixels, size*size)
+	}
+	if len(result.Image) == 0 {
+		t.Errorf("expected a non-empty diff image")
+	}
+}
+
+func TestPerceptualDiffIgnoresAntiAliasedEdges(t *testing.T) {
+	const size = 8
+
+	// A single-pixel-wide edge has no interior, so every changed pixel
+	// sits on the boundary and should be rejected as anti-aliasing noise.
+	expected := solidImage(size, size, color.White)
+	actual := solidImage(size, size, color.White)
+	actual.Set(4, 4, color.Black)
+
+	result, err := PerceptualDiff("0000", "ffff", encodePNG(t, expected), encodePNG(t, actual))
+	if err != nil {
+		t.Fatalf("PerceptualDiff returned error: %v", err)
+	}
+
+	if !result.Equal {
+		t.Errorf("expected a lone edge pixel to be rejected as anti-aliasing, got ChangedPixels=%d", result.ChangedPixels)
+	}
+}
@@ -0,0 +1,157 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ResultsStore persists the most recent check run's artifacts (metadata,
+// the actual screenshot, and any failing diff) so `visage report` can
+// render them without re-running the checks.
+type ResultsStore struct {
+	Root string
+}
+
+func NewResultsStore(root string) *ResultsStore {
+	return &ResultsStore{Root: root}
+}
+
+func (r *ResultsStore) dir(component, story, viewport string) string {
+	return filepath.Join(r.Root, component, story, viewport)
+}
+
+func (r *ResultsStore) Save(result RegressionTestResult) error {
+	dir := r.dir(result.ComponentName, result.StoryName, result.CurrentTest.Viewport)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	metadata, err := json.MarshalIndent(result.CurrentTest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "result.json"), metadata, 0o644); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "status.txt"), []byte(result.Status), 0o644); err != nil {
+		return err
+	}
+
+	if result.Screenshot != nil {
+		if err := os.WriteFile(filepath.Join(dir, "actual.png"), result.Screenshot, 0o644); err != nil {
+			return err
+		}
+	}
+
+	if result.DiffImage != nil {
+		if err := os.WriteFile(filepath.Join(dir, "diff.png"), result.DiffImage, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Load reads back the result previously written by Save for one
+// component/story/viewport, so a single result can be re-approved without
+// re-running the whole check.
+func (r *ResultsStore) Load(component, story, viewport string) (*RegressionTestResult, error) {
+	dir := r.dir(component, story, viewport)
+
+	metadata, err := os.ReadFile(filepath.Join(dir, "result.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var test RegressionTest
+	if err := json.Unmarshal(metadata, &test); err != nil {
+		return nil, err
+	}
+
+	status, err := os.ReadFile(filepath.Join(dir, "status.txt"))
+	if err != nil {
+		return nil, err
+	}
+
+	screenshot, err := os.ReadFile(filepath.Join(dir, "actual.png"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return &RegressionTestResult{
+		Status: string(status),
+		ComponentName: component,
+		StoryName: story,
+		CurrentTest: test,
+		Screenshot: screenshot,
+	}, nil
+}
+
+// StoredResult is one (component, story, viewport) entry discovered by List.
+type StoredResult struct {
+	Component string
+	Story string
+	Viewport string
+	Status string
+	ActualPath string
+	DiffPath string
+	ExpectedPath string
+}
+
+// List walks the results store and, for each entry, resolves the expected
+// (approved baseline) image path alongside it. A store that hasn't had a
+// single result saved to it yet (no check has ever run) is not an error —
+// it just has nothing to list.
+func (r *ResultsStore) List(baselineRoot string) ([]StoredResult, error) {
+	if !FileExists(r.Root) {
+		return nil, nil
+	}
+
+	var stored []StoredResult
+
+	err := filepath.Walk(r.Root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Name() != "status.txt" {
+			return nil
+		}
+
+		viewportDir := filepath.Dir(path)
+		rel, err := filepath.Rel(r.Root, viewportDir)
+		if err != nil {
+			return err
+		}
+
+		parts := strings.Split(rel, string(filepath.Separator))
+		if len(parts) != 3 {
+			return nil
+		}
+
+		status, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		stored = append(stored, StoredResult{
+			Component: parts[0],
+			Story: parts[1],
+			Viewport: parts[2],
+			Status: string(status),
+			ActualPath: filepath.Join(viewportDir, "actual.png"),
+			DiffPath: filepath.Join(viewportDir, "diff.png"),
+			ExpectedPath: filepath.Join(baselineRoot, parts[0], parts[1], parts[2]+".png"),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
@@ -0,0 +1,204 @@
+// Package report renders a self-contained HTML report from a completed
+// visage check run: a thumbnail grid with pass/fail filtering and
+// click-to-expand comparisons, with every image inlined as a data URI so
+// the output is a single file engineers can open or attach to CI.
+package report
+
+import (
+	"encoding/base64"
+	"html/template"
+	"os"
+	"sort"
+)
+
+// Result is one (component, story, viewport) entry to render. Image paths
+// are read from disk and inlined; a missing or empty path is simply
+// skipped in the output.
+type Result struct {
+	Component string
+	Story string
+	Viewport string
+	Status string
+	ExpectedImagePath string
+	ActualImagePath string
+	DiffImagePath string
+}
+
+type row struct {
+	Result
+	ExpectedImageDataURI string
+	ActualImageDataURI string
+	DiffImageDataURI string
+}
+
+type reportData struct {
+	Rows []row
+	Total int
+	Passed int
+	Failed int
+	Created int
+}
+
+// Generate writes a self-contained report.html for results to outputPath.
+func Generate(results []Result, outputPath string) error {
+	rows := make([]row, 0, len(results))
+	for _, result := range results {
+		rows = append(rows, row{
+			Result: result,
+			ExpectedImageDataURI: imageDataURI(result.ExpectedImagePath),
+			ActualImageDataURI: imageDataURI(result.ActualImagePath),
+			DiffImageDataURI: imageDataURI(result.DiffImagePath),
+		})
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Component != rows[j].Component {
+			return rows[i].Component < rows[j].Component
+		}
+		if rows[i].Story != rows[j].Story {
+			return rows[i].Story < rows[j].Story
+		}
+		return rows[i].Viewport < rows[j].Viewport
+	})
+
+	data := reportData{
+		Rows: rows,
+		Total: len(rows),
+		Passed: countStatus(rows, "passed"),
+		Failed: countStatus(rows, "failed"),
+		Created: countStatus(rows, "created"),
+	}
+
+	tmpl, err := template.New("report").Parse(reportTemplate)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return tmpl.Execute(file, data)
+}
+
+func countStatus(rows []row, status string) int {
+	count := 0
+	for _, r := range rows {
+		if r.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func imageDataURI(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(content)
+}
+
+const reportTemplate = `<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>visage report</title>
+<style>
+  body { font-family: -apple-system, sans-serif; margin: 0; background: #0d1117; color: #c9d1d9; }
+  header { padding: 1rem 1.5rem; border-bottom: 1px solid #30363d; display: flex; align-items: center; gap: 1rem; }
+  header h1 { font-size: 1rem; margin: 0; }
+  .stat { font-size: 0.85rem; }
+  .stat.passed { color: #3fb950; }
+  .stat.failed { color: #f85149; }
+  .stat.created { color: #d29922; }
+  .filters { margin-left: auto; display: flex; gap: 0.5rem; }
+  .filters button { background: #21262d; color: #c9d1d9; border: 1px solid #30363d; border-radius: 6px; padding: 0.25rem 0.75rem; cursor: pointer; }
+  .filters button.active { background: #1f6feb; border-color: #1f6feb; }
+  .grid { display: grid; grid-template-columns: repeat(auto-fill, minmax(220px, 1fr)); gap: 1rem; padding: 1.5rem; }
+  .card { background: #161b22; border: 1px solid #30363d; border-radius: 8px; overflow: hidden; cursor: pointer; }
+  .card img { width: 100%; display: block; background: #fff; }
+  .card .meta { padding: 0.5rem 0.75rem; font-size: 0.8rem; }
+  .card .meta .status { text-transform: uppercase; font-size: 0.7rem; font-weight: 600; }
+  .card.passed .status { color: #3fb950; }
+  .card.failed .status { color: #f85149; }
+  .card.created .status { color: #d29922; }
+  .card.hidden { display: none; }
+  dialog { background: #161b22; color: #c9d1d9; border: 1px solid #30363d; border-radius: 8px; max-width: 95vw; }
+  dialog .compare { display: flex; gap: 1rem; padding: 1rem; }
+  dialog .compare figure { margin: 0; }
+  dialog .compare img { max-width: 30vw; max-height: 70vh; background: #fff; }
+  dialog .close { float: right; background: none; border: none; color: #c9d1d9; font-size: 1.25rem; cursor: pointer; }
+</style>
+</head>
+<body>
+<header>
+  <h1>visage report</h1>
+  <span class="stat">{{.Total}} stories</span>
+  <span class="stat passed">{{.Passed}} passed</span>
+  <span class="stat failed">{{.Failed}} failed</span>
+  <span class="stat created">{{.Created}} created</span>
+  <div class="filters">
+    <button data-filter="all" class="active">All</button>
+    <button data-filter="failed">Failed</button>
+    <button data-filter="passed">Passed</button>
+    <button data-filter="created">Created</button>
+  </div>
+</header>
+<div class="grid" id="grid">
+{{range .Rows}}
+  <div class="card {{.Status}}" data-status="{{.Status}}">
+    {{if .ActualImageDataURI}}<img src="{{.ActualImageDataURI}}" loading="lazy">{{end}}
+    <div class="meta">
+      <div class="status">{{.Status}}</div>
+      <div>{{.Component}} / {{.Story}}</div>
+      <div>{{.Viewport}}</div>
+    </div>
+    <template class="compare-data">
+      <span class="expected">{{.ExpectedImageDataURI}}</span>
+      <span class="actual">{{.ActualImageDataURI}}</span>
+      <span class="diff">{{.DiffImageDataURI}}</span>
+    </template>
+  </div>
+{{end}}
+</div>
+<dialog id="lightbox">
+  <button class="close" onclick="document.getElementById('lightbox').close()">&times;</button>
+  <div class="compare">
+    <figure><figcaption>expected</figcaption><img id="lightbox-expected"></figure>
+    <figure><figcaption>actual</figcaption><img id="lightbox-actual"></figure>
+    <figure><figcaption>diff</figcaption><img id="lightbox-diff"></figure>
+  </div>
+</dialog>
+<script>
+document.querySelectorAll('.filters button').forEach(function (button) {
+  button.addEventListener('click', function () {
+    document.querySelectorAll('.filters button').forEach(function (b) { b.classList.remove('active'); });
+    button.classList.add('active');
+    var filter = button.dataset.filter;
+    document.querySelectorAll('.card').forEach(function (card) {
+      card.classList.toggle('hidden', filter !== 'all' && card.dataset.status !== filter);
+    });
+  });
+});
+
+document.querySelectorAll('.card').forEach(function (card) {
+  card.addEventListener('click', function () {
+    var data = card.querySelector('.compare-data').content;
+    document.getElementById('lightbox-expected').src = data.querySelector('.expected').textContent;
+    document.getElementById('lightbox-actual').src = data.querySelector('.actual').textContent;
+    document.getElementById('lightbox-diff').src = data.querySelector('.diff').textContent;
+    document.getElementById('lightbox').showModal();
+  });
+});
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,45 @@
+package server
+
+import "sync"
+
+// broker fans a stream of SSE messages out to every currently-connected
+// client, dropping messages for a client whose buffer is full rather than
+// blocking the run that's producing them.
+type broker struct {
+	mu sync.Mutex
+	clients map[chan string]bool
+}
+
+func newBroker() *broker {
+	return &broker{clients: make(map[chan string]bool)}
+}
+
+func (b *broker) subscribe() chan string {
+	ch := make(chan string, 16)
+
+	b.mu.Lock()
+	b.clients[ch] = true
+	b.mu.Unlock()
+
+	return ch
+}
+
+func (b *broker) unsubscribe(ch chan string) {
+	b.mu.Lock()
+	delete(b.clients, ch)
+	b.mu.Unlock()
+
+	close(ch)
+}
+
+func (b *broker) publish(message string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.clients {
+		select {
+		case ch <- message:
+		default:
+		}
+	}
+}
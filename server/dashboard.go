@@ -0,0 +1,104 @@
+package server
+
+import "html/template"
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(dashboardHTML))
+
+const dashboardHTML = `<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>visage</title>
+<style>
+  body { font-family: -apple-system, sans-serif; margin: 0; background: #0d1117; color: #c9d1d9; }
+  header { padding: 1rem 1.5rem; border-bottom: 1px solid #30363d; display: flex; align-items: center; gap: 1rem; }
+  header h1 { font-size: 1rem; margin: 0; }
+  #run-state { font-size: 0.85rem; color: #8b949e; }
+  .grid { display: grid; grid-template-columns: repeat(auto-fill, minmax(260px, 1fr)); gap: 1rem; padding: 1.5rem; }
+  .card { background: #161b22; border: 1px solid #30363d; border-radius: 8px; overflow: hidden; }
+  .card .images { display: flex; }
+  .card .images img { width: 50%; display: block; background: #fff; object-fit: contain; }
+  .card .meta { padding: 0.5rem 0.75rem; font-size: 0.8rem; }
+  .card .meta .status { text-transform: uppercase; font-size: 0.7rem; font-weight: 600; }
+  .card.passed .status { color: #3fb950; }
+  .card.failed .status { color: #f85149; }
+  .card.created .status { color: #d29922; }
+  .card.skipped .status { color: #8b949e; }
+  .card button { margin-top: 0.5rem; background: #238636; color: #fff; border: none; border-radius: 6px; padding: 0.35rem 0.75rem; cursor: pointer; font-size: 0.75rem; }
+  .card button:disabled { background: #30363d; cursor: default; }
+</style>
+</head>
+<body>
+<header>
+  <h1>visage</h1>
+  <span id="run-state"></span>
+</header>
+<div class="grid" id="grid"></div>
+<script>
+function render(results) {
+  var grid = document.getElementById('grid');
+  grid.innerHTML = '';
+
+  results.forEach(function (result) {
+    var card = document.createElement('div');
+    card.className = 'card ' + result.status;
+
+    var images = document.createElement('div');
+    images.className = 'images';
+    [result.expectedImage, result.actualImage].forEach(function (src) {
+      if (!src) return;
+      var img = document.createElement('img');
+      img.src = src;
+      images.appendChild(img);
+    });
+    card.appendChild(images);
+
+    var meta = document.createElement('div');
+    meta.className = 'meta';
+    meta.innerHTML = '<div class="status">' + result.status + '</div>' +
+      '<div>' + result.component + ' / ' + result.story + '</div>' +
+      '<div>' + result.viewport + '</div>';
+    card.appendChild(meta);
+
+    var approve = document.createElement('button');
+    approve.textContent = 'Approve';
+    approve.disabled = result.status === 'passed';
+    approve.addEventListener('click', function () {
+      approve.disabled = true;
+      fetch('/api/approve', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ component: result.component, story: result.story, viewport: result.viewport }),
+      }).then(reload);
+    });
+    meta.appendChild(approve);
+
+    grid.appendChild(card);
+  });
+}
+
+function reload() {
+  fetch('/api/results').then(function (r) { return r.json(); }).then(render);
+}
+
+var runState = document.getElementById('run-state');
+var source = new EventSource('/events');
+source.onmessage = function (event) {
+  var data = JSON.parse(event.data);
+  if (data.type === 'run-started') {
+    runState.textContent = 'running...';
+  } else if (data.type === 'run-finished') {
+    runState.textContent = '';
+    reload();
+  } else if (data.type === 'run-error') {
+    runState.textContent = 'error: ' + data.error;
+  } else if (data.type === 'approved') {
+    reload();
+  }
+};
+
+reload();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/maxischmaxi/visage/core"
+)
+
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboardTemplate.Execute(w, nil); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+type resultView struct {
+	Component string `json:"component"`
+	Story string `json:"story"`
+	Viewport string `json:"viewport"`
+	Status string `json:"status"`
+	ExpectedImage string `json:"expectedImage,omitempty"`
+	ActualImage string `json:"actualImage,omitempty"`
+	DiffImage string `json:"diffImage,omitempty"`
+}
+
+func (s *Server) handleResults(w http.ResponseWriter, r *http.Request) {
+	snapshot := s.snapshot()
+	views := make([]resultView, 0, len(snapshot))
+
+	for _, result := range snapshot {
+		views = append(views, resultView{
+			Component: result.Component,
+			Story: result.Story,
+			Viewport: result.Viewport,
+			Status: result.Status,
+			ExpectedImage: s.imageURL(result.ExpectedPath),
+			ActualImage: s.imageURL(result.ActualPath),
+			DiffImage: s.imageURL(result.DiffPath),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(views)
+}
+
+// imageURL turns an absolute path under .visage into a /images/ URL the
+// dashboard can load; it returns "" if the file doesn't exist.
+func (s *Server) imageURL(path string) string {
+	if !core.FileExists(path) {
+		return ""
+	}
+
+	rel, err := filepath.Rel(filepath.Join(s.Path, ".visage"), path)
+	if err != nil {
+		return ""
+	}
+
+	return "/images/" + filepath.ToSlash(rel)
+}
+
+type approveRequest struct {
+	Component string `json:"component"`
+	Story string `json:"story"`
+	Viewport string `json:"viewport"`
+}
+
+func (s *Server) handleApprove(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req approveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	store := core.NewResultsStore(s.resultsRoot())
+	result, err := store.Load(req.Component, req.Story, req.Viewport)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	baseline := core.NewFilesystemBaseline(s.baselineRoot())
+	if err := baseline.Save(req.Component, req.Story, req.Viewport, result.CurrentTest, result.Screenshot); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := s.refreshResults(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.events.publish(fmt.Sprintf(`{"type":"approved","component":%q,"story":%q,"viewport":%q}`, req.Component, req.Story, req.Viewport))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := s.events.subscribe()
+	defer s.events.unsubscribe(ch)
+
+	for {
+		select {
+		case message, ok := <-ch:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "data: %s\n\n", message)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// handleImage serves the expected/actual/diff PNGs referenced by the
+// dashboard, rooted at the project's .visage directory so a request can't
+// read arbitrary files off disk.
+func (s *Server) handleImage(w http.ResponseWriter, r *http.Request) {
+	rel := strings.TrimPrefix(r.URL.Path, "/images/")
+	visageRoot := filepath.Join(s.Path, ".visage")
+
+	path := filepath.Join(visageRoot, rel)
+
+	relToRoot, err := filepath.Rel(visageRoot, path)
+	if err != nil || relToRoot == ".." || strings.HasPrefix(relToRoot, ".."+string(filepath.Separator)) {
+		http.Error(w, "invalid path", http.StatusBadRequest)
+		return
+	}
+
+	http.ServeFile(w, r, path)
+}
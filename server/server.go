@@ -0,0 +1,129 @@
+// Package server implements `visage serve`: a local HTTP dashboard over a
+// project's last check results, with an SSE stream for background runs
+// and a filesystem watcher that kicks off a new (non-forced) check
+// whenever a story's dependencies change.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync"
+
+	"github.com/maxischmaxi/visage/core"
+)
+
+// Server owns the state backing the dashboard: the project being watched,
+// its config, a chromedp allocator context shared across runs, the most
+// recent results, and the SSE broker that announces changes to them.
+type Server struct {
+	Path string
+	Config *core.Config
+	AllocCtx context.Context
+
+	mu sync.RWMutex
+	results []core.StoredResult
+
+	events *broker
+}
+
+// New builds a Server for path, ready to have ListenAndServe called on it.
+func New(path string, config *core.Config, allocCtx context.Context) *Server {
+	return &Server{
+		Path: path,
+		Config: config,
+		AllocCtx: allocCtx,
+		events: newBroker(),
+	}
+}
+
+// ListenAndServe starts the filesystem watcher, runs an initial check in
+// the background, and serves the dashboard on addr until an error occurs.
+func (s *Server) ListenAndServe(addr string) error {
+	if err := s.refreshResults(); err != nil {
+		return err
+	}
+
+	if err := s.watch(); err != nil {
+		return err
+	}
+
+	go s.runCheck(false)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleDashboard)
+	mux.HandleFunc("/api/results", s.handleResults)
+	mux.HandleFunc("/api/approve", s.handleApprove)
+	mux.HandleFunc("/events", s.handleEvents)
+	mux.HandleFunc("/images/", s.handleImage)
+
+	fmt.Printf("visage serve listening on %s\n", addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+func (s *Server) baselineRoot() string {
+	return filepath.Join(s.Path, ".visage", "baselines")
+}
+
+func (s *Server) resultsRoot() string {
+	return filepath.Join(s.Path, ".visage", "results")
+}
+
+// runCheck runs a check, publishing an SSE event per result as it lands
+// and a final "run-finished" event once the whole run (and the results
+// refresh it triggers) has completed.
+func (s *Server) runCheck(force bool) {
+	s.events.publish(`{"type":"run-started"}`)
+
+	_, err := s.Config.CheckStream(s.AllocCtx, s.Path, force, func(result core.RegressionTestResult) {
+		message, err := json.Marshal(map[string]any{
+			"type": "result",
+			"component": result.ComponentName,
+			"story": result.StoryName,
+			"viewport": result.CurrentTest.Viewport,
+			"status": result.Status,
+		})
+		if err == nil {
+			s.events.publish(string(message))
+		}
+	})
+	if err != nil {
+		message, _ := json.Marshal(map[string]any{"type": "run-error", "error": err.Error()})
+		s.events.publish(string(message))
+		return
+	}
+
+	if err := s.refreshResults(); err != nil {
+		message, _ := json.Marshal(map[string]any{"type": "run-error", "error": err.Error()})
+		s.events.publish(string(message))
+		return
+	}
+
+	s.events.publish(`{"type":"run-finished"}`)
+}
+
+func (s *Server) refreshResults() error {
+	store := core.NewResultsStore(s.resultsRoot())
+
+	results, err := store.List(s.baselineRoot())
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.results = results
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *Server) snapshot() []core.StoredResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]core.StoredResult, len(s.results))
+	copy(results, s.results)
+	return results
+}
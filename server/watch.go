@@ -0,0 +1,79 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watch re-runs a (non-forced) check whenever a file under s.Path changes,
+// debounced so a save that touches several files only triggers one run.
+// Config.CheckStream already skips stories whose transitive deps are
+// unchanged and that have an approved baseline, so this stays cheap even
+// on a broad directory change.
+func (s *Server) watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	if err := addRecursive(watcher, s.Path); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		var debounce *time.Timer
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+					continue
+				}
+
+				if debounce != nil {
+					debounce.Stop()
+				}
+				debounce = time.AfterFunc(300*time.Millisecond, func() {
+					s.runCheck(false)
+				})
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func addRecursive(watcher *fsnotify.Watcher, root string) error {
+	ignoreDirs := map[string]bool{
+		"node_modules": true,
+		".visage": true,
+		".git": true,
+	}
+
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if ignoreDirs[info.Name()] {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}